@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awskinesisexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awskinesisexporter"
+
+import (
+	"compress/gzip"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awskinesisexporter/internal/compress"
+)
+
+// CompressionConfig controls how records are compressed before being handed to the Kinesis
+// PutRecord(s) call.
+type CompressionConfig struct {
+	// Format selects the compression codec: "none", "noop", "gzip", "zlib", "flate", "zstd",
+	// "snappy", "lz4" or "pgzip". Defaults to "none".
+	Format string `mapstructure:"compression"`
+
+	// CompressionLevel trades CPU for size and is only meaningful for "gzip", "zlib", "flate",
+	// "zstd" and "pgzip". Left unset, each codec's own default level is used.
+	CompressionLevel *int `mapstructure:"compression_level"`
+
+	// PgzipBlockSize is the block size, in bytes, pgzip splits input into before compressing
+	// blocks concurrently. Only used when Format is "pgzip". Left unset (0), pgzip's 1 MiB
+	// default is used.
+	PgzipBlockSize int `mapstructure:"pgzip_block_size"`
+
+	// PgzipBlocks is the number of blocks pgzip is allowed to compress concurrently. Only used
+	// when Format is "pgzip". Left unset (0), GOMAXPROCS is used.
+	PgzipBlocks int `mapstructure:"pgzip_blocks"`
+}
+
+// Validate returns an error if the compression settings cannot be turned into a working
+// compress.Compressor.
+func (c *CompressionConfig) Validate() error {
+	if _, err := c.newCompressor(); err != nil {
+		return fmt.Errorf("compression: %w", err)
+	}
+	return nil
+}
+
+// newCompressor builds the compress.Compressor described by this config.
+func (c *CompressionConfig) newCompressor() (compress.Compressor, error) {
+	format := c.Format
+	if format == "" {
+		format = "none"
+	}
+
+	if format == "pgzip" {
+		level := gzip.DefaultCompression
+		if c.CompressionLevel != nil {
+			level = *c.CompressionLevel
+		}
+		return compress.NewPgzipCompressor(level, c.PgzipBlockSize, c.PgzipBlocks)
+	}
+
+	if c.CompressionLevel != nil {
+		return compress.NewCompressorWithLevel(format, *c.CompressionLevel)
+	}
+
+	return compress.NewCompressor(format)
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awskinesisexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	level := 5
+	outOfRange := 99
+
+	testCases := []struct {
+		name    string
+		cfg     CompressionConfig
+		wantErr bool
+	}{
+		{name: "default", cfg: CompressionConfig{}},
+		{name: "gzip", cfg: CompressionConfig{Format: "gzip"}},
+		{name: "gzip with level", cfg: CompressionConfig{Format: "gzip", CompressionLevel: &level}},
+		{name: "gzip with out-of-range level", cfg: CompressionConfig{Format: "gzip", CompressionLevel: &outOfRange}, wantErr: true},
+		{name: "zstd", cfg: CompressionConfig{Format: "zstd"}},
+		{name: "snappy", cfg: CompressionConfig{Format: "snappy"}},
+		{name: "lz4", cfg: CompressionConfig{Format: "lz4"}},
+		{name: "pgzip", cfg: CompressionConfig{Format: "pgzip", PgzipBlockSize: 1 << 18, PgzipBlocks: 4}},
+		{name: "pgzip with out-of-range level", cfg: CompressionConfig{Format: "pgzip", CompressionLevel: &outOfRange}, wantErr: true},
+		{name: "unknown format", cfg: CompressionConfig{Format: "bogus"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				assert.Error(t, err, "Must error for invalid compression settings")
+				return
+			}
+			assert.NoError(t, err, "Must not error for valid compression settings")
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package compress // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awskinesisexporter/internal/compress"
+
+// AdaptiveResult is the outcome of an adaptive compression attempt: whether the wrapped codec was
+// actually used, and the bytes to send to Kinesis either way.
+type AdaptiveResult struct {
+	Data       []byte
+	Compressed bool
+}
+
+// AdaptiveCompressor is like Compressor, but reports back whether compression was applied so the
+// exporter can set the matching Kinesis record attribute.
+type AdaptiveCompressor func(message []byte) (AdaptiveResult, error)
+
+// NewAdaptiveCompressor wraps inner so that it is skipped for payloads it is unlikely to help
+// with, such as already-dense OTLP protobuf. It compresses a sampleBytes-sized prefix of each
+// message, and only runs inner over the full message when that sample's compression ratio is at
+// least minRatio; otherwise the original message is returned uncompressed. A sampleBytes of 0 (or
+// greater than len(message)) samples the whole message.
+func NewAdaptiveCompressor(inner Compressor, minRatio float64, sampleBytes int) AdaptiveCompressor {
+	return func(message []byte) (AdaptiveResult, error) {
+		sample := message
+		if sampleBytes > 0 && sampleBytes < len(sample) {
+			sample = sample[:sampleBytes]
+		}
+
+		if len(sample) == 0 {
+			return AdaptiveResult{Data: message, Compressed: false}, nil
+		}
+
+		compressedSample, err := inner(sample)
+		if err != nil {
+			return AdaptiveResult{}, err
+		}
+
+		if compressionRatio(len(sample), len(compressedSample)) < minRatio {
+			return AdaptiveResult{Data: message, Compressed: false}, nil
+		}
+
+		compressed, err := inner(message)
+		if err != nil {
+			return AdaptiveResult{}, err
+		}
+
+		return AdaptiveResult{Data: compressed, Compressed: true}, nil
+	}
+}
+
+// compressionRatio returns the fraction of originalLen saved by compressing down to compressedLen,
+// e.g. 0.75 means the compressed form is a quarter of the original size.
+func compressionRatio(originalLen, compressedLen int) float64 {
+	return 1 - float64(compressedLen)/float64(originalLen)
+}
@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package compress // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awskinesisexporter/internal/compress"
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Compressor is used to compress the incoming byte array before handing it off to the Kinesis PutRecord(s) call.
+type Compressor func(message []byte) ([]byte, error)
+
+type writeCloserReset interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// boundedPool retains up to maxSize items for reuse. get never blocks: when the pool is empty it
+// mints a transient item via newItem instead of waiting for one to be returned, exactly like a
+// plain sync.Pool would. put never blocks either: once maxSize items are already retained, the
+// extra one is simply dropped and left for the GC, which keeps the heap from growing unbounded
+// under a burst of concurrent PutRecords calls without ever serializing callers on a semaphore.
+type boundedPool struct {
+	items   chan any
+	newItem func() any
+}
+
+func newBoundedPool(maxSize int, newItem func() any) *boundedPool {
+	return &boundedPool{
+		items:   make(chan any, maxSize),
+		newItem: newItem,
+	}
+}
+
+func (p *boundedPool) get() any {
+	select {
+	case v := <-p.items:
+		return v
+	default:
+		return p.newItem()
+	}
+}
+
+func (p *boundedPool) put(v any) {
+	select {
+	case p.items <- v:
+	default:
+	}
+}
+
+type compressor struct {
+	writers *boundedPool
+	buffers *boundedPool
+}
+
+func newCompressor(newWriter func() writeCloserReset) *compressor {
+	maxPooled := runtime.GOMAXPROCS(0)
+
+	return &compressor{
+		writers: newBoundedPool(maxPooled, func() any { return newWriter() }),
+		buffers: newBoundedPool(maxPooled, func() any { return new(bytes.Buffer) }),
+	}
+}
+
+func (c *compressor) compress(message []byte) ([]byte, error) {
+	w := c.writers.get().(writeCloserReset)
+	defer c.writers.put(w)
+
+	buf := c.buffers.get().(*bytes.Buffer)
+	buf.Reset()
+	defer c.buffers.put(buf)
+
+	w.Reset(buf)
+
+	if _, err := w.Write(message); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+
+	return out, nil
+}
+
+// defaultLevel returns the level NewCompressor uses for a given format when the caller has no
+// opinion on the CPU/size trade-off.
+func defaultLevel(key string) int {
+	switch key {
+	case "zlib":
+		return zlib.DefaultCompression
+	case "flate":
+		return flate.DefaultCompression
+	case "gzip", "pgzip":
+		return gzip.DefaultCompression
+	case "zstd":
+		return int(zstd.SpeedDefault)
+	default:
+		return 0
+	}
+}
+
+// validateLevel checks that level is within the range the underlying codec accepts. Formats that
+// have no notion of a level (snappy, lz4, noop) accept any value, since it is simply ignored.
+func validateLevel(key string, level int) error {
+	switch key {
+	case "zlib", "gzip", "pgzip":
+		if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+			return fmt.Errorf("compression level %d out of range [%d, %d] for format %q", level, gzip.HuffmanOnly, gzip.BestCompression, key)
+		}
+	case "flate":
+		if level < flate.HuffmanOnly || level > flate.BestCompression {
+			return fmt.Errorf("compression level %d out of range [%d, %d] for format %q", level, flate.HuffmanOnly, flate.BestCompression, key)
+		}
+	case "zstd":
+		if level < int(zstd.SpeedFastest) || level > int(zstd.SpeedBestCompression) {
+			return fmt.Errorf("compression level %d out of range [%d, %d] for format %q", level, int(zstd.SpeedFastest), int(zstd.SpeedBestCompression), key)
+		}
+	}
+	return nil
+}
+
+func writerFactory(key string, level int) (func() writeCloserReset, error) {
+	if err := validateLevel(key, level); err != nil {
+		return nil, err
+	}
+
+	switch key {
+	case "zlib":
+		if _, err := zlib.NewWriterLevel(nil, level); err != nil {
+			return nil, err
+		}
+		return func() writeCloserReset {
+			w, _ := zlib.NewWriterLevel(nil, level)
+			return w
+		}, nil
+	case "flate":
+		if _, err := flate.NewWriter(nil, level); err != nil {
+			return nil, err
+		}
+		return func() writeCloserReset {
+			w, _ := flate.NewWriter(nil, level)
+			return w
+		}, nil
+	case "gzip":
+		if _, err := gzip.NewWriterLevel(nil, level); err != nil {
+			return nil, err
+		}
+		return func() writeCloserReset {
+			w, _ := gzip.NewWriterLevel(nil, level)
+			return w
+		}, nil
+	case "zstd":
+		if _, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevel(level))); err != nil {
+			return nil, err
+		}
+		return func() writeCloserReset {
+			w, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevel(level)))
+			return w
+		}, nil
+	case "pgzip":
+		return newPgzipWriter(level, 0, 0)
+	case "snappy":
+		return func() writeCloserReset { return snappy.NewBufferedWriter(nil) }, nil
+	case "lz4":
+		return func() writeCloserReset { return lz4.NewWriter(nil) }, nil
+	case "noop", "none":
+		return func() writeCloserReset { return &noop{} }, nil
+	default:
+		return nil, fmt.Errorf("unknown compression format: %s", key)
+	}
+}
+
+// NewCompressor returns a function capable of taking a []byte and compressing it using the given
+// format, at that format's default compression level.
+func NewCompressor(key string) (Compressor, error) {
+	return NewCompressorWithLevel(key, defaultLevel(key))
+}
+
+// NewCompressorWithLevel is like NewCompressor, but lets the caller trade CPU for size. The
+// accepted range depends on format: see compress/gzip, compress/zlib, compress/flate and
+// github.com/klauspost/compress/zstd for the level each one accepts. Formats without a notion of
+// level (snappy, lz4, noop) ignore it.
+func NewCompressorWithLevel(key string, level int) (Compressor, error) {
+	newWriter, err := writerFactory(key, level)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCompressor(newWriter).compress, nil
+}
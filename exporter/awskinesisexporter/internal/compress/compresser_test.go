@@ -5,7 +5,9 @@ package compress_test
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
+	"compress/zlib"
 	"fmt"
 	"io"
 	"math/rand"
@@ -13,6 +15,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -30,6 +35,10 @@ func TestCompressorFormats(t *testing.T) {
 		{format: "gzip"},
 		{format: "zlib"},
 		{format: "flate"},
+		{format: "zstd"},
+		{format: "snappy"},
+		{format: "lz4"},
+		{format: "pgzip"},
 	}
 
 	source := rand.NewSource(time.Now().UnixMilli())
@@ -63,6 +72,118 @@ func TestCompressorFormats(t *testing.T) {
 	assert.Error(t, err, "Must error when an invalid compression format is given")
 }
 
+func TestNewCompressorWithLevel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		format  string
+		level   int
+		wantErr bool
+	}{
+		{format: "gzip", level: gzip.BestSpeed},
+		{format: "gzip", level: gzip.DefaultCompression},
+		{format: "gzip", level: gzip.BestCompression},
+		{format: "gzip", level: gzip.BestCompression + 1, wantErr: true},
+		{format: "zlib", level: zlib.BestSpeed},
+		{format: "zlib", level: zlib.BestCompression},
+		{format: "zlib", level: zlib.HuffmanOnly - 1, wantErr: true},
+		{format: "flate", level: flate.BestSpeed},
+		{format: "flate", level: flate.BestCompression},
+		{format: "flate", level: flate.HuffmanOnly - 1, wantErr: true},
+		{format: "zstd", level: int(zstd.SpeedFastest)},
+		{format: "zstd", level: int(zstd.SpeedBestCompression)},
+		{format: "zstd", level: int(zstd.SpeedBestCompression) + 1, wantErr: true},
+		{format: "snappy", level: 9}, // ignored, snappy has no notion of level
+		{format: "lz4", level: 9},    // ignored, lz4 has no notion of level
+	}
+
+	const data = "You know nothing Jon Snow"
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("format_%s_level_%d", tc.format, tc.level), func(t *testing.T) {
+			c, err := compress.NewCompressorWithLevel(tc.format, tc.level)
+			if tc.wantErr {
+				assert.Error(t, err, "Must error for an out-of-range compression level")
+				return
+			}
+			require.NoError(t, err, "Must have a valid compression level")
+			require.NotNil(t, c, "Must have a valid compressor")
+
+			out, err := c([]byte(data))
+			assert.NoError(t, err, "Must not error when processing data")
+			assert.NotNil(t, out, "Must have a valid record")
+
+			dc, err := decompressFormat(tc.format, out)
+			require.NoError(t, err, "Must be able to decompress what was compressed")
+			assert.Equal(t, []byte(data), dc, "Must round-trip back to the original data at this level")
+		})
+	}
+}
+
+// decompressFormat reverses the given format so round-trip tests can assert on the original
+// bytes regardless of the compression level used to produce out.
+func decompressFormat(format string, out []byte) ([]byte, error) {
+	switch format {
+	case "gzip":
+		return decompress(out)
+	case "zlib":
+		r, err := zlib.NewReader(bytes.NewReader(out))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case "flate":
+		r := flate.NewReader(bytes.NewReader(out))
+		defer r.Close()
+		return io.ReadAll(r)
+	case "snappy":
+		return io.ReadAll(snappy.NewReader(bytes.NewReader(out)))
+	case "lz4":
+		return io.ReadAll(lz4.NewReader(bytes.NewReader(out)))
+	case "zstd":
+		r, err := zstd.NewReader(bytes.NewReader(out))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	default:
+		return out, nil
+	}
+}
+
+func TestAdaptiveCompressor(t *testing.T) {
+	t.Parallel()
+
+	inner, err := compress.NewCompressor("gzip")
+	require.NoError(t, err, "Must have a valid compressor")
+
+	source := rand.NewSource(time.Now().UnixMilli())
+	genRand := rand.New(source)
+
+	// high-entropy data, matching the existing data2 fixture: gzip cannot shrink this
+	// meaningfully, so the adaptive path should fall through to the original bytes.
+	data2 := make([]byte, 1065)
+	for i := 0; i < 1065; i++ {
+		data2[i] = byte(genRand.Int31())
+	}
+
+	adaptive := compress.NewAdaptiveCompressor(inner, 0.2, 256)
+
+	result, err := adaptive(data2)
+	require.NoError(t, err, "Must not error when processing data")
+	assert.False(t, result.Compressed, "High-entropy data must not be compressed")
+	assert.Equal(t, data2, result.Data, "Must return the original slice untouched")
+
+	// low-entropy data compresses well, so the adaptive path should fall through to gzip.
+	lowEntropy := bytes.Repeat([]byte("You know nothing Jon Snow. "), 64)
+
+	result, err = adaptive(lowEntropy)
+	require.NoError(t, err, "Must not error when processing data")
+	assert.True(t, result.Compressed, "Low-entropy data must be compressed")
+	assert.Less(t, len(result.Data), len(lowEntropy), "Compressed form must be smaller than the input")
+}
+
 func decompress(input []byte) ([]byte, error) {
 	r, err := gzip.NewReader(bytes.NewReader(input))
 	if err != nil {
@@ -111,6 +232,85 @@ func BenchmarkGzipCompressor_1Mb(b *testing.B) {
 	benchmarkCompressor(b, "gzip", 131072)
 }
 
+func BenchmarkZstdCompressor_1000Bytes(b *testing.B) {
+	benchmarkCompressor(b, "zstd", 1000)
+}
+
+func BenchmarkZstdCompressor_1Mb(b *testing.B) {
+	benchmarkCompressor(b, "zstd", 131072)
+}
+
+func BenchmarkSnappyCompressor_1000Bytes(b *testing.B) {
+	benchmarkCompressor(b, "snappy", 1000)
+}
+
+func BenchmarkSnappyCompressor_1Mb(b *testing.B) {
+	benchmarkCompressor(b, "snappy", 131072)
+}
+
+func BenchmarkLz4Compressor_1000Bytes(b *testing.B) {
+	benchmarkCompressor(b, "lz4", 1000)
+}
+
+func BenchmarkLz4Compressor_1Mb(b *testing.B) {
+	benchmarkCompressor(b, "lz4", 131072)
+}
+
+func BenchmarkGzipCompressor_256KiB(b *testing.B) {
+	benchmarkCompressor(b, "gzip", 262144)
+}
+
+func BenchmarkGzipCompressor_1MiB(b *testing.B) {
+	benchmarkCompressor(b, "gzip", 1048576)
+}
+
+func BenchmarkPgzipCompressor_256KiB(b *testing.B) {
+	benchmarkCompressor(b, "pgzip", 262144)
+}
+
+func BenchmarkPgzipCompressor_1MiB(b *testing.B) {
+	benchmarkCompressor(b, "pgzip", 1048576)
+}
+
+func BenchmarkGzipCompressor_Parallel_2000(b *testing.B) {
+	benchmarkCompressorParallel(b, "gzip", 2000)
+}
+
+func BenchmarkGzipCompressor_Parallel_20000(b *testing.B) {
+	benchmarkCompressorParallel(b, "gzip", 20000)
+}
+
+func BenchmarkGzipCompressor_Parallel_100000(b *testing.B) {
+	benchmarkCompressorParallel(b, "gzip", 100000)
+}
+
+func benchmarkCompressorParallel(b *testing.B, format string, length int) {
+	b.Helper()
+
+	source := rand.NewSource(time.Now().UnixMilli())
+	genRand := rand.New(source)
+
+	compressor, err := compress.NewCompressor(format)
+	require.NoError(b, err, "Must not error when given a valid format")
+	require.NotNil(b, compressor, "Must have a valid compressor")
+
+	data := make([]byte, length)
+	for i := 0; i < length; i++ {
+		data[i] = byte(genRand.Int31())
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			out, err := compressor(data)
+			assert.NoError(b, err, "Must not error when processing data")
+			assert.NotNil(b, out, "Must have a valid byte array after")
+		}
+	})
+}
+
 func benchmarkCompressor(b *testing.B, format string, length int) {
 	b.Helper()
 
@@ -212,3 +412,49 @@ func concurrentCompressFunc(t *testing.T) {
 		t.Errorf("Error encountered on concurrent compression: %v", err)
 	}
 }
+
+// TestCompressorHighConcurrency drives far more concurrent callers than GOMAXPROCS through a
+// single Compressor. The writer/buffer pool must mint transient instances for callers beyond its
+// retained size rather than block them, so this is expected to finish quickly regardless of
+// GOMAXPROCS; if get() ever goes back to serializing callers on a semaphore, this test times out.
+func TestCompressorHighConcurrency(t *testing.T) {
+	t.Parallel()
+
+	compressFunc, err := compress.NewCompressor("gzip")
+	require.NoError(t, err, "Must not error when given a valid format")
+
+	const numWorkers = 64
+	const iterationsPerWorker = 50
+
+	data := make([]byte, 2048)
+	source := rand.NewSource(time.Now().UnixMilli())
+	genRand := rand.New(source)
+	for i := range data {
+		data[i] = byte(genRand.Int31())
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterationsPerWorker; j++ {
+				_, err := compressFunc(data)
+				assert.NoError(t, err, "Must not error when processing data")
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Compressor did not keep up with concurrent callers beyond GOMAXPROCS in time; the writer/buffer pool may be serializing callers instead of minting transient instances")
+	}
+}
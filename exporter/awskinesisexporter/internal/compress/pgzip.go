@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package compress // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awskinesisexporter/internal/compress"
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/klauspost/pgzip"
+)
+
+// defaultPgzipBlockSize is the block size pgzip splits input into before compressing blocks
+// concurrently. 1 MiB mirrors the Kinesis per-record size limit, so an aggregated PutRecords
+// payload that is close to that limit still only needs a handful of blocks.
+const defaultPgzipBlockSize = 1 << 20
+
+// newPgzipWriter returns a writeCloserReset factory for the given level/blockSize/blocks. It
+// eagerly builds one writer to surface any error from pgzip.NewWriterLevel or SetConcurrency
+// (e.g. a blockSize below pgzip's internal minimum) before handing back a factory that ignores
+// those same, now-known-good, errors on every subsequent call.
+func newPgzipWriter(level, blockSize, blocks int) (func() writeCloserReset, error) {
+	if blockSize <= 0 {
+		blockSize = defaultPgzipBlockSize
+	}
+	if blocks <= 0 {
+		blocks = runtime.GOMAXPROCS(0)
+	}
+
+	build := func() (*pgzip.Writer, error) {
+		w, err := pgzip.NewWriterLevel(nil, level)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.SetConcurrency(blockSize, blocks); err != nil {
+			return nil, fmt.Errorf("pgzip concurrency %d blocks of %d bytes: %w", blocks, blockSize, err)
+		}
+		return w, nil
+	}
+
+	if _, err := build(); err != nil {
+		return nil, err
+	}
+
+	return func() writeCloserReset {
+		w, _ := build()
+		return w
+	}, nil
+}
+
+// NewPgzipCompressor is like NewCompressorWithLevel("pgzip", level), but lets the caller tune the
+// block size and number of blocks pgzip is allowed to compress concurrently, see
+// pgzip.Writer.SetConcurrency. A non-positive blockSize or blocks falls back to the package
+// defaults (1 MiB blocks, GOMAXPROCS blocks in flight).
+func NewPgzipCompressor(level, blockSize, blocks int) (Compressor, error) {
+	if err := validateLevel("pgzip", level); err != nil {
+		return nil, err
+	}
+
+	newWriter, err := newPgzipWriter(level, blockSize, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCompressor(newWriter).compress, nil
+}